@@ -0,0 +1,13 @@
+package supervisorctl
+
+// AvailEntry describes one process and the group it belongs to, as
+// configured in supervisord, regardless of whether it's currently running,
+// as reported by
+// "supervisorctl avail" or the getAllConfigInfo RPC call.
+type AvailEntry struct {
+	Name      string `json:"name"`
+	Group     string `json:"group"`
+	InUse     bool   `json:"in_use"`
+	AutoStart bool   `json:"auto_start"`
+	Priority  int    `json:"priority"`
+}
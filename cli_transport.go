@@ -0,0 +1,481 @@
+package supervisorctl
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cmd interface for executing commands
+type Cmd interface {
+	Run() error
+	Output() ([]byte, error)
+	StdoutPipe() (io.ReadCloser, error)
+	Start() error
+	Wait() error
+}
+
+// CommandExecutor interface for executing commands
+type CommandExecutor interface {
+	Command(name string, arg ...string) Cmd
+	CommandContext(ctx context.Context, name string, arg ...string) Cmd
+}
+
+// DefaultCommandExecutor implements CommandExecutor using os/exec
+type DefaultCommandExecutor struct{}
+
+func (d *DefaultCommandExecutor) Command(name string, arg ...string) Cmd {
+	return exec.Command(name, arg...)
+}
+
+func (d *DefaultCommandExecutor) CommandContext(ctx context.Context, name string, arg ...string) Cmd {
+	return exec.CommandContext(ctx, name, arg...)
+}
+
+// cliTransport implements Transport by shelling out to the supervisorctl
+// binary, which must be on PATH and able to reach the target supervisord
+// (directly or over ssh, as configured in configFile).
+type cliTransport struct {
+	configFile string
+	executor   CommandExecutor
+}
+
+func (t *cliTransport) args(rest ...string) []string {
+	if t.configFile != "" {
+		return append([]string{"-c", t.configFile}, rest...)
+	}
+	return rest
+}
+
+func (t *cliTransport) run(args ...string) ([]byte, error) {
+	cmd := t.executor.Command("supervisorctl", t.args(args...)...)
+	return cmd.Output()
+}
+
+func (t *cliTransport) runCtx(ctx context.Context, args ...string) ([]byte, error) {
+	cmd := t.executor.CommandContext(ctx, "supervisorctl", t.args(args...)...)
+	return cmd.Output()
+}
+
+// Status returns the status of supervisor programs.
+// If no names are provided, returns status for all programs.
+func (t *cliTransport) Status(names []string) ([]ProgramInfo, error) {
+	args := []string{"status"}
+	args = append(args, names...)
+
+	output, err := t.run(args...)
+	partial := false
+	if err != nil {
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			switch exitErr.ExitCode() {
+			case 3, 4:
+				// supervisorctl exits 3 or 4 when at least one named
+				// program couldn't be found or is in a bad state; it
+				// still prints status lines for the rest on stdout.
+				partial = true
+			default:
+				return nil, fmt.Errorf("failed to get status: %w - output %s ", err, string(output))
+			}
+		} else {
+			return nil, fmt.Errorf("failed to get status: %w", err)
+		}
+	}
+
+	var programs []ProgramInfo
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		program, err := parseStatusLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse status line: %w", err)
+		}
+		programs = append(programs, program)
+	}
+
+	if partial {
+		return programs, ErrPartialStatus
+	}
+	return programs, nil
+}
+
+func (t *cliTransport) StartProcess(name string) error {
+	if _, err := t.run("start", name); err != nil {
+		return fmt.Errorf("failed to start program: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) StopProcess(name string) error {
+	if _, err := t.run("stop", name); err != nil {
+		return fmt.Errorf("failed to stop program: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) RestartProcess(name string) error {
+	if _, err := t.run("restart", name); err != nil {
+		return fmt.Errorf("failed to restart program: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) StartProcessGroup(name string) error {
+	if _, err := t.run("start", name+":*"); err != nil {
+		return fmt.Errorf("failed to start group: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) StopProcessGroup(name string) error {
+	if _, err := t.run("stop", name+":*"); err != nil {
+		return fmt.Errorf("failed to stop group: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) RestartProcessGroup(name string) error {
+	if _, err := t.run("restart", name+":*"); err != nil {
+		return fmt.Errorf("failed to restart group: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) AddProcessGroup(ctx context.Context, name string) error {
+	if _, err := t.runCtx(ctx, "add", name); err != nil {
+		return fmt.Errorf("failed to add process group: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) RemoveProcessGroup(ctx context.Context, name string) error {
+	if _, err := t.runCtx(ctx, "remove", name); err != nil {
+		return fmt.Errorf("failed to remove process group: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) Reread(ctx context.Context) (added, changed, removed []string, err error) {
+	output, err := t.runCtx(ctx, "reread")
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to reread config: %w", err)
+	}
+	return parseReread(string(output))
+}
+
+func (t *cliTransport) Update(ctx context.Context, names ...string) error {
+	args := append([]string{"update"}, names...)
+	if _, err := t.runCtx(ctx, args...); err != nil {
+		return fmt.Errorf("failed to update: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) Reload(ctx context.Context) error {
+	if _, err := t.runCtx(ctx, "reload"); err != nil {
+		return fmt.Errorf("failed to reload: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) Shutdown(ctx context.Context) error {
+	if _, err := t.runCtx(ctx, "shutdown"); err != nil {
+		return fmt.Errorf("failed to shut down: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) SignalProcesses(ctx context.Context, signal string, names ...string) error {
+	if len(names) == 0 {
+		names = []string{"all"}
+	}
+	args := append([]string{"signal", signal}, names...)
+	if _, err := t.runCtx(ctx, args...); err != nil {
+		return fmt.Errorf("failed to signal: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) ClearProcessLogs(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		names = []string{"all"}
+	}
+	args := append([]string{"clear"}, names...)
+	if _, err := t.runCtx(ctx, args...); err != nil {
+		return fmt.Errorf("failed to clear logs: %w", err)
+	}
+	return nil
+}
+
+func (t *cliTransport) Pid(ctx context.Context, name string) (int, error) {
+	args := []string{"pid"}
+	if name != "" {
+		args = append(args, name)
+	}
+	output, err := t.runCtx(ctx, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get pid: %w", err)
+	}
+	pid, err := strconv.Atoi(strings.TrimSpace(string(output)))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse pid: %w", err)
+	}
+	return pid, nil
+}
+
+func (t *cliTransport) Avail(ctx context.Context) ([]AvailEntry, error) {
+	output, err := t.runCtx(ctx, "avail")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list avail: %w", err)
+	}
+
+	var entries []AvailEntry
+	scanner := bufio.NewScanner(bytes.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		entry, err := parseAvailLine(line)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse avail line: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Tail streams a program's log by invoking "supervisorctl tail -f" (or
+// "tail" for opts.Follow == false) and reading its stdout line by line.
+// The child is killed, ending the stream, when ctx is cancelled.
+func (t *cliTransport) Tail(ctx context.Context, name string, opts TailOptions) (<-chan LogLine, error) {
+	args := []string{"tail"}
+	if opts.Follow {
+		args = append(args, "-f")
+	} else if opts.Bytes > 0 {
+		args = append(args, fmt.Sprintf("-%d", opts.Bytes))
+	}
+	args = append(args, name)
+	stream := opts.Stream
+	if stream == StreamStderr {
+		args = append(args, "stderr")
+	} else {
+		stream = StreamStdout
+	}
+
+	cmd := t.executor.CommandContext(ctx, "supervisorctl", t.args(args...)...)
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open tail pipe: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start tail: %w", err)
+	}
+
+	ch := make(chan LogLine)
+	go func() {
+		defer close(ch)
+		defer cmd.Wait()
+		scanner := bufio.NewScanner(stdout)
+		for scanner.Scan() {
+			select {
+			case ch <- LogLine{Name: name, Stream: stream, Line: scanner.Text()}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch, nil
+}
+
+// ReadLog reads the last length bytes (default 1600, supervisorctl's own
+// default) of a program's log via "supervisorctl tail". The CLI has no
+// notion of an offset or overflow, so offset is ignored and newOffset is
+// simply offset plus the number of bytes read; overflow is always false.
+func (t *cliTransport) ReadLog(ctx context.Context, name string, stream Stream, offset, length int) ([]byte, int, bool, error) {
+	if length <= 0 {
+		length = 1600
+	}
+	args := []string{"tail", fmt.Sprintf("-%d", length), name}
+	if stream == StreamStderr {
+		args = append(args, "stderr")
+	}
+
+	output, err := t.runCtx(ctx, args...)
+	if err != nil {
+		return nil, 0, false, fmt.Errorf("failed to read log: %w", err)
+	}
+	return output, offset + len(output), false, nil
+}
+
+// parseReread parses the output of "supervisorctl reread", which reports
+// zero or more of three lines depending on what changed:
+//
+//	Added groups: foo, bar
+//	Changed groups: baz
+//	Removed groups: qux
+func parseReread(output string) (added, changed, removed []string, err error) {
+	scanner := bufio.NewScanner(strings.NewReader(output))
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "Added groups: "):
+			added = splitGroupList(strings.TrimPrefix(line, "Added groups: "))
+		case strings.HasPrefix(line, "Changed groups: "):
+			changed = splitGroupList(strings.TrimPrefix(line, "Changed groups: "))
+		case strings.HasPrefix(line, "Removed groups: "):
+			removed = splitGroupList(strings.TrimPrefix(line, "Removed groups: "))
+		}
+	}
+	return added, changed, removed, nil
+}
+
+func splitGroupList(s string) []string {
+	parts := strings.Split(s, ",")
+	names := make([]string, 0, len(parts))
+	for _, p := range parts {
+		if name := strings.TrimSpace(p); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// parseAvailLine parses one line of "supervisorctl avail" output, e.g.:
+//
+//	program1                         in use    auto      priority:999
+//	group:program2                   avail     manual    priority:500
+func parseAvailLine(line string) (AvailEntry, error) {
+	fields := strings.Fields(line)
+	if len(fields) < 3 {
+		return AvailEntry{}, fmt.Errorf("invalid avail line: %s", line)
+	}
+
+	namespec := fields[0]
+	i := 1
+
+	inUse := fields[i] == "in"
+	if inUse {
+		i++ // skip "use"
+	}
+	i++
+
+	if i >= len(fields) {
+		return AvailEntry{}, fmt.Errorf("invalid avail line: %s", line)
+	}
+	autoStart := fields[i] == "auto"
+	i++
+
+	if i >= len(fields) {
+		return AvailEntry{}, fmt.Errorf("invalid avail line: %s", line)
+	}
+	priority, _ := strconv.Atoi(strings.TrimPrefix(fields[i], "priority:"))
+
+	group, name := namespec, namespec
+	if idx := strings.Index(namespec, ":"); idx >= 0 {
+		group, name = namespec[:idx], namespec[idx+1:]
+	}
+
+	return AvailEntry{
+		Name:      name,
+		Group:     group,
+		InUse:     inUse,
+		AutoStart: autoStart,
+		Priority:  priority,
+	}, nil
+}
+
+// parseStatusLine parses a single line of supervisorctl status output.
+func parseStatusLine(line string) (ProgramInfo, error) {
+	parts := strings.Fields(line)
+	if len(parts) < 3 {
+		return ProgramInfo{}, fmt.Errorf("invalid status line: %s", line)
+	}
+
+	name := parts[0]
+	state := parts[1]
+	description := strings.Join(parts[2:], " ")
+
+	// Handle "no such process" error
+	if strings.Contains(description, "no such process") {
+		return ProgramInfo{
+			Name:        name,
+			State:       StateUnknown,
+			Description: "no such process",
+		}, nil
+	}
+
+	stateCode, err := ParseProcessState(state)
+	if err != nil {
+		return ProgramInfo{}, fmt.Errorf("unknown state: %s", state)
+	}
+
+	var pid int
+	var uptime time.Duration
+	if strings.Contains(description, "pid") {
+		pidStr := strings.Split(description, "pid")[1]
+		pidStr = strings.TrimSpace(strings.Split(pidStr, ",")[0])
+		pid, _ = strconv.Atoi(pidStr)
+
+		if strings.Contains(description, "uptime") {
+			uptimeParts := strings.Split(description, "uptime")
+			if len(uptimeParts) > 1 {
+				uptimeStr := strings.TrimSpace(strings.Split(uptimeParts[1], ",")[0])
+				uptime, err = parseUptime(uptimeStr)
+				if err != nil {
+					return ProgramInfo{}, fmt.Errorf("failed to parse uptime: %w", err)
+				}
+			}
+		}
+	}
+
+	return ProgramInfo{
+		Name:        name,
+		State:       stateCode,
+		Description: description,
+		PID:         pid,
+		Uptime:      uptime,
+	}, nil
+}
+
+// parseUptime parses the uptime text supervisorctl's status output
+// embeds in a program's description, in the form "H:MM:SS" or, once the
+// process has run for a full day, "D days, H:MM:SS".
+func parseUptime(s string) (time.Duration, error) {
+	days := int64(0)
+	if idx := strings.Index(s, " days, "); idx >= 0 {
+		d, err := strconv.ParseInt(s[:idx], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("invalid uptime %q: %w", s, err)
+		}
+		days = d
+		s = s[idx+len(" days, "):]
+	}
+
+	parts := strings.Split(s, ":")
+	if len(parts) != 3 {
+		return 0, fmt.Errorf("invalid uptime %q", s)
+	}
+	hours, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid uptime %q: %w", s, err)
+	}
+	minutes, err := strconv.ParseInt(parts[1], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid uptime %q: %w", s, err)
+	}
+	seconds, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid uptime %q: %w", s, err)
+	}
+
+	return time.Duration(days)*24*time.Hour +
+		time.Duration(hours)*time.Hour +
+		time.Duration(minutes)*time.Minute +
+		time.Duration(seconds)*time.Second, nil
+}
@@ -0,0 +1,207 @@
+package supervisorctl
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseReread(t *testing.T) {
+	tests := []struct {
+		name            string
+		output          string
+		expectedAdded   []string
+		expectedChanged []string
+		expectedRemoved []string
+	}{
+		{
+			name:            "nothing changed",
+			output:          "",
+			expectedAdded:   nil,
+			expectedChanged: nil,
+			expectedRemoved: nil,
+		},
+		{
+			name:            "all three",
+			output:          "Added groups: foo, bar\nChanged groups: baz\nRemoved groups: qux\n",
+			expectedAdded:   []string{"foo", "bar"},
+			expectedChanged: []string{"baz"},
+			expectedRemoved: []string{"qux"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			added, changed, removed, err := parseReread(tt.output)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedAdded, added)
+			assert.Equal(t, tt.expectedChanged, changed)
+			assert.Equal(t, tt.expectedRemoved, removed)
+		})
+	}
+}
+
+func TestParseAvailLine(t *testing.T) {
+	tests := []struct {
+		name         string
+		line         string
+		expectedInfo AvailEntry
+	}{
+		{
+			name: "in use, autostart",
+			line: "program1                         in use    auto      priority:999",
+			expectedInfo: AvailEntry{
+				Name:      "program1",
+				Group:     "program1",
+				InUse:     true,
+				AutoStart: true,
+				Priority:  999,
+			},
+		},
+		{
+			name: "avail, manual, grouped",
+			line: "mygroup:program2                avail     manual    priority:500",
+			expectedInfo: AvailEntry{
+				Name:      "program2",
+				Group:     "mygroup",
+				InUse:     false,
+				AutoStart: false,
+				Priority:  500,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := parseAvailLine(tt.line)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedInfo, info)
+		})
+	}
+}
+
+func TestClientReread(t *testing.T) {
+	mockExecutor := new(MockCommandExecutor)
+	mockCmd := &MockCmd{MockOutput: []byte("Added groups: foo\n")}
+	mockExecutor.On("CommandContext", "supervisorctl", []string{"reread"}).Return(mockCmd)
+
+	client := &Client{transport: &cliTransport{executor: mockExecutor}}
+	added, changed, removed, err := client.Reread(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo"}, added)
+	assert.Nil(t, changed)
+	assert.Nil(t, removed)
+}
+
+func TestClientSignal(t *testing.T) {
+	tests := []struct {
+		name          string
+		names         []string
+		expectedArgs  []string
+		expectedError error
+	}{
+		{
+			name:         "specific processes",
+			names:        []string{"program1"},
+			expectedArgs: []string{"signal", "HUP", "program1"},
+		},
+		{
+			name:         "defaults to all",
+			names:        nil,
+			expectedArgs: []string{"signal", "HUP", "all"},
+		},
+		{
+			name:          "command error",
+			names:         []string{"program1"},
+			expectedArgs:  []string{"signal", "HUP", "program1"},
+			expectedError: errors.New("command failed"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExecutor := new(MockCommandExecutor)
+			mockCmd := &MockCmd{Err: tt.expectedError}
+			mockExecutor.On("CommandContext", "supervisorctl", tt.expectedArgs).Return(mockCmd)
+
+			client := &Client{transport: &cliTransport{executor: mockExecutor}}
+			err := client.Signal(context.Background(), "HUP", tt.names...)
+
+			if tt.expectedError != nil {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			mockExecutor.AssertExpectations(t)
+		})
+	}
+}
+
+func TestClientPid(t *testing.T) {
+	tests := []struct {
+		name         string
+		programName  string
+		expectedArgs []string
+		output       string
+		expectedPid  int
+	}{
+		{
+			name:         "supervisord's own pid",
+			programName:  "",
+			expectedArgs: []string{"pid"},
+			output:       "2676\n",
+			expectedPid:  2676,
+		},
+		{
+			name:         "a program's pid",
+			programName:  "program1",
+			expectedArgs: []string{"pid", "program1"},
+			output:       "123\n",
+			expectedPid:  123,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockExecutor := new(MockCommandExecutor)
+			mockCmd := &MockCmd{MockOutput: []byte(tt.output)}
+			mockExecutor.On("CommandContext", "supervisorctl", tt.expectedArgs).Return(mockCmd)
+
+			client := &Client{transport: &cliTransport{executor: mockExecutor}}
+			pid, err := client.Pid(context.Background(), tt.programName)
+
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedPid, pid)
+		})
+	}
+}
+
+func TestParseUptime(t *testing.T) {
+	tests := []struct {
+		name     string
+		uptime   string
+		expected time.Duration
+		wantErr  bool
+	}{
+		{name: "under a minute", uptime: "0:00:45", expected: 45 * time.Second},
+		{name: "over an hour", uptime: "1:23:45", expected: 1*time.Hour + 23*time.Minute + 45*time.Second},
+		{name: "over a day", uptime: "1 days, 1:00:00", expected: 25 * time.Hour},
+		{name: "invalid", uptime: "not-an-uptime", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseUptime(tt.uptime)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
@@ -0,0 +1,93 @@
+package supervisorctl
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Supervisor XML-RPC fault codes, as defined by supervisor's xmlrpc.Faults.
+const (
+	faultUnknownMethod        = 1
+	faultIncorrectParameters  = 2
+	faultBadArguments         = 3
+	faultSignatureUnsupported = 4
+	faultShutdownState        = 6
+	faultBadName              = 10
+	faultBadSignal            = 11
+	faultNoFile               = 20
+	faultNotExecutable        = 21
+	faultFailed               = 30
+	faultAbnormalTermination  = 40
+	faultSpawnError           = 50
+	faultAlreadyStarted       = 60
+	faultNotRunning           = 70
+	faultAlreadyAdded         = 90
+	faultStillRunning         = 91
+	faultCantReread           = 92
+)
+
+// Sentinel errors returned by RPC-backed operations. Each corresponds to one
+// of the fault codes above and is reachable via errors.Is on an error
+// returned by an RPCTransport for that fault, so callers can branch on them
+// without string matching on the fault message. The other fault codes above
+// (e.g. faultNoFile, faultAlreadyAdded) have no sentinel.
+var (
+	ErrBadName             = errors.New("supervisorctl: bad process name")
+	ErrBadSignal           = errors.New("supervisorctl: bad signal")
+	ErrAlreadyStarted      = errors.New("supervisorctl: already started")
+	ErrNotRunning          = errors.New("supervisorctl: not running")
+	ErrSpawnError          = errors.New("supervisorctl: spawn error")
+	ErrAbnormalTermination = errors.New("supervisorctl: abnormal termination")
+	ErrStillRunning        = errors.New("supervisorctl: still running")
+	ErrCantReread          = errors.New("supervisorctl: can't reread config")
+	ErrShutdownState       = errors.New("supervisorctl: supervisor is shutting down")
+
+	// ErrPartialStatus is returned by a CLI-backed Status when
+	// supervisorctl exits with a status indicating some, but not all,
+	// programs could be queried (e.g. a named program doesn't exist).
+	// The programs that were successfully parsed are still returned
+	// alongside this error.
+	ErrPartialStatus = errors.New("supervisorctl: partial status")
+)
+
+// RPCFault is the error returned for an XML-RPC fault response from
+// supervisord. Use errors.Is against the sentinel errors above to branch on
+// well-known fault codes; RPCFault.Error always carries the raw code and
+// fault string for logging.
+type RPCFault struct {
+	Code   int
+	String string
+}
+
+func (f *RPCFault) Error() string {
+	return fmt.Sprintf("supervisor fault %d: %s", f.Code, f.String)
+}
+
+// Unwrap maps well-known fault codes onto the sentinel errors above. Codes
+// with no sentinel, including any supervisor may add in the future, fall
+// through to nil, so errors.Is simply won't match any sentinel rather than
+// matching the wrong one.
+func (f *RPCFault) Unwrap() error {
+	switch f.Code {
+	case faultBadName:
+		return ErrBadName
+	case faultBadSignal:
+		return ErrBadSignal
+	case faultAlreadyStarted:
+		return ErrAlreadyStarted
+	case faultNotRunning:
+		return ErrNotRunning
+	case faultSpawnError:
+		return ErrSpawnError
+	case faultAbnormalTermination:
+		return ErrAbnormalTermination
+	case faultStillRunning:
+		return ErrStillRunning
+	case faultCantReread:
+		return ErrCantReread
+	case faultShutdownState:
+		return ErrShutdownState
+	default:
+		return nil
+	}
+}
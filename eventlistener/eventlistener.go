@@ -0,0 +1,192 @@
+// Package eventlistener implements supervisord's event listener protocol:
+// a subprocess that supervisord pipes notifications to over stdin, using a
+// READY / header+payload / RESULT handshake (see
+// http://supervisord.org/events.html).
+package eventlistener
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Result is returned from an event handler to tell supervisord whether the
+// event was processed successfully.
+type Result int
+
+const (
+	ResultOK Result = iota
+	ResultFail
+)
+
+func (r Result) String() string {
+	if r == ResultFail {
+		return "FAIL"
+	}
+	return "OK"
+}
+
+// Event type names, as sent in the header's eventname field. See
+// http://supervisord.org/events.html for the full catalog.
+const (
+	EventProcessStateStarting = "PROCESS_STATE_STARTING"
+	EventProcessStateRunning  = "PROCESS_STATE_RUNNING"
+	EventProcessStateBackoff  = "PROCESS_STATE_BACKOFF"
+	EventProcessStateStopping = "PROCESS_STATE_STOPPING"
+	EventProcessStateExited   = "PROCESS_STATE_EXITED"
+	EventProcessStateStopped  = "PROCESS_STATE_STOPPED"
+	EventProcessStateFatal    = "PROCESS_STATE_FATAL"
+	EventProcessStateUnknown  = "PROCESS_STATE_UNKNOWN"
+
+	EventProcessCommunicationStdout = "PROCESS_COMMUNICATION_STDOUT"
+	EventProcessCommunicationStderr = "PROCESS_COMMUNICATION_STDERR"
+	EventProcessLogStdout           = "PROCESS_LOG_STDOUT"
+	EventProcessLogStderr           = "PROCESS_LOG_STDERR"
+
+	EventRemoteCommunication = "REMOTE_COMMUNICATION"
+
+	EventSupervisorStateChangeRunning  = "SUPERVISOR_STATE_CHANGE_RUNNING"
+	EventSupervisorStateChangeStopping = "SUPERVISOR_STATE_CHANGE_STOPPING"
+
+	EventProcessGroupAdded   = "PROCESS_GROUP_ADDED"
+	EventProcessGroupRemoved = "PROCESS_GROUP_REMOVED"
+
+	EventTick5    = "TICK_5"
+	EventTick60   = "TICK_60"
+	EventTick3600 = "TICK_3600"
+)
+
+// Event is one notification supervisord sends to an event listener,
+// combining its header and payload "key:val" pairs. Not every field
+// applies to every event type (e.g. only PROCESS_STATE_* events carry a
+// FromState); Raw holds every field for event types this struct doesn't
+// model explicitly.
+type Event struct {
+	Type        string
+	Serial      int64
+	Pool        string
+	ProcessName string
+	GroupName   string
+	FromState   string
+	PID         int
+	Expected    bool
+	Raw         map[string]string
+	Payload     []byte
+}
+
+// Listener drives supervisord's event listener handshake over an
+// arbitrary io.Reader/io.Writer pair, normally a process's stdin/stdout.
+type Listener struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// NewListener creates a Listener that reads event notifications from in
+// and writes READY/RESULT handshakes to out.
+func NewListener(in io.Reader, out io.Writer) *Listener {
+	return &Listener{in: bufio.NewReader(in), out: out}
+}
+
+// Run drives the listener loop: announce readiness, read one event,
+// dispatch it to handle, report the result, and repeat. It returns nil if
+// the input stream ends cleanly (supervisord closed the listener's
+// stdin), or the first error encountered otherwise. ctx is checked before
+// each READY; once it is done, Run returns ctx.Err() there. A read already
+// waiting for the next event is not interrupted by ctx.
+func (l *Listener) Run(ctx context.Context, handle func(Event) Result) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if err := l.writeReady(); err != nil {
+			return fmt.Errorf("write READY: %w", err)
+		}
+
+		event, err := l.readEvent()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read event: %w", err)
+		}
+
+		if err := l.writeResult(handle(event)); err != nil {
+			return fmt.Errorf("write RESULT: %w", err)
+		}
+	}
+}
+
+func (l *Listener) writeReady() error {
+	_, err := io.WriteString(l.out, "READY\n")
+	return err
+}
+
+func (l *Listener) writeResult(r Result) error {
+	data := r.String()
+	_, err := fmt.Fprintf(l.out, "RESULT %d\n%s", len(data), data)
+	return err
+}
+
+func (l *Listener) readEvent() (Event, error) {
+	headerLine, err := l.in.ReadString('\n')
+	if err != nil {
+		return Event{}, err
+	}
+	header := parseKeyVals(headerLine)
+
+	length, err := strconv.Atoi(header["len"])
+	if err != nil {
+		return Event{}, fmt.Errorf("header %q: missing or invalid len", strings.TrimSpace(headerLine))
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(l.in, payload); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return Event{}, fmt.Errorf("read %d byte payload: %w", length, err)
+	}
+	body := parseKeyVals(string(payload))
+
+	raw := make(map[string]string, len(header)+len(body))
+	for k, v := range header {
+		raw[k] = v
+	}
+	for k, v := range body {
+		raw[k] = v
+	}
+
+	serial, _ := strconv.ParseInt(header["serial"], 10, 64)
+	pid, _ := strconv.Atoi(body["pid"])
+
+	return Event{
+		Type:        header["eventname"],
+		Serial:      serial,
+		Pool:        header["pool"],
+		ProcessName: body["processname"],
+		GroupName:   body["groupname"],
+		FromState:   body["from_state"],
+		PID:         pid,
+		Expected:    body["expected"] == "1",
+		Raw:         raw,
+		Payload:     payload,
+	}, nil
+}
+
+// parseKeyVals parses a space-separated "key:val key:val ..." string, the
+// format supervisor uses for both event headers and payloads.
+func parseKeyVals(s string) map[string]string {
+	fields := strings.Fields(s)
+	out := make(map[string]string, len(fields))
+	for _, f := range fields {
+		if i := strings.IndexByte(f, ':'); i >= 0 {
+			out[f[:i]] = f[i+1:]
+		}
+	}
+	return out
+}
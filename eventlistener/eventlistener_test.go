@@ -0,0 +1,76 @@
+package eventlistener
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListenerRun(t *testing.T) {
+	payload := "processname:cat groupname:cat from_state:STARTING expected:1 pid:2766"
+	header := fmt.Sprintf("ver:3.0 server:supervisor serial:21 pool:listener poolserial:10 eventname:PROCESS_STATE_RUNNING len:%d\n", len(payload))
+
+	in := bytes.NewBufferString(header + payload)
+	var out bytes.Buffer
+
+	var got Event
+	err := NewListener(in, &out).Run(context.Background(), func(e Event) Result {
+		got = e
+		return ResultOK
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "READY\nRESULT 2\nOKREADY\n", out.String())
+	assert.Equal(t, EventProcessStateRunning, got.Type)
+	assert.Equal(t, int64(21), got.Serial)
+	assert.Equal(t, "listener", got.Pool)
+	assert.Equal(t, "cat", got.ProcessName)
+	assert.Equal(t, "cat", got.GroupName)
+	assert.Equal(t, "STARTING", got.FromState)
+	assert.Equal(t, 2766, got.PID)
+	assert.True(t, got.Expected)
+	assert.Equal(t, []byte(payload), got.Payload)
+}
+
+func TestListenerRunFailResult(t *testing.T) {
+	payload := "processname:cat groupname:cat from_state:STARTING expected:0 pid:2766"
+	header := fmt.Sprintf("eventname:PROCESS_STATE_EXITED len:%d\n", len(payload))
+
+	in := bytes.NewBufferString(header + payload)
+	var out bytes.Buffer
+
+	err := NewListener(in, &out).Run(context.Background(), func(e Event) Result {
+		assert.False(t, e.Expected)
+		return ResultFail
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, "READY\nRESULT 4\nFAILREADY\n", out.String())
+}
+
+func TestListenerRunEOF(t *testing.T) {
+	in := bytes.NewBufferString("")
+	var out bytes.Buffer
+
+	called := false
+	err := NewListener(in, &out).Run(context.Background(), func(e Event) Result {
+		called = true
+		return ResultOK
+	})
+
+	assert.NoError(t, err)
+	assert.False(t, called)
+	assert.Equal(t, "READY\n", out.String())
+}
+
+func TestParseKeyVals(t *testing.T) {
+	got := parseKeyVals("ver:3.0 server:supervisor serial:21\n")
+	assert.Equal(t, map[string]string{
+		"ver":    "3.0",
+		"server": "supervisor",
+		"serial": "21",
+	}, got)
+}
@@ -0,0 +1,68 @@
+package supervisorctl
+
+import "fmt"
+
+// ProcessState is one of the states supervisord's process state machine can
+// be in (see http://supervisord.org/subprocess.html#process-states).
+type ProcessState int
+
+const (
+	StateStopped  ProcessState = 0
+	StateStarting ProcessState = 10
+	StateRunning  ProcessState = 20
+	StateBackoff  ProcessState = 30
+	StateStopping ProcessState = 40
+	StateExited   ProcessState = 100
+	StateFatal    ProcessState = 200
+	StateUnknown  ProcessState = 1000
+)
+
+// String returns the name supervisorctl itself displays for the state,
+// e.g. "RUNNING".
+func (s ProcessState) String() string {
+	switch s {
+	case StateStopped:
+		return "STOPPED"
+	case StateStarting:
+		return "STARTING"
+	case StateRunning:
+		return "RUNNING"
+	case StateBackoff:
+		return "BACKOFF"
+	case StateStopping:
+		return "STOPPING"
+	case StateExited:
+		return "EXITED"
+	case StateFatal:
+		return "FATAL"
+	case StateUnknown:
+		return "UNKNOWN"
+	default:
+		return fmt.Sprintf("ProcessState(%d)", int(s))
+	}
+}
+
+// ParseProcessState parses the state name supervisorctl displays (e.g.
+// "RUNNING") into its ProcessState.
+func ParseProcessState(name string) (ProcessState, error) {
+	switch name {
+	case "STOPPED":
+		return StateStopped, nil
+	case "STARTING":
+		return StateStarting, nil
+	case "RUNNING":
+		return StateRunning, nil
+	case "BACKOFF":
+		return StateBackoff, nil
+	case "STOPPING":
+		return StateStopping, nil
+	case "EXITED":
+		return StateExited, nil
+	case "FATAL":
+		return StateFatal, nil
+	case "UNKNOWN":
+		return StateUnknown, nil
+	default:
+		return 0, fmt.Errorf("unknown process state: %s", name)
+	}
+}
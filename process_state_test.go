@@ -0,0 +1,62 @@
+package supervisorctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestProcessStateString(t *testing.T) {
+	tests := []struct {
+		name     string
+		state    ProcessState
+		expected string
+	}{
+		{name: "stopped", state: StateStopped, expected: "STOPPED"},
+		{name: "starting", state: StateStarting, expected: "STARTING"},
+		{name: "running", state: StateRunning, expected: "RUNNING"},
+		{name: "backoff", state: StateBackoff, expected: "BACKOFF"},
+		{name: "stopping", state: StateStopping, expected: "STOPPING"},
+		{name: "exited", state: StateExited, expected: "EXITED"},
+		{name: "fatal", state: StateFatal, expected: "FATAL"},
+		{name: "unknown", state: StateUnknown, expected: "UNKNOWN"},
+		{name: "unrecognized code", state: ProcessState(7), expected: "ProcessState(7)"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, tt.state.String())
+		})
+	}
+}
+
+func TestParseProcessState(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected ProcessState
+		wantErr  bool
+	}{
+		{name: "stopped", input: "STOPPED", expected: StateStopped},
+		{name: "starting", input: "STARTING", expected: StateStarting},
+		{name: "running", input: "RUNNING", expected: StateRunning},
+		{name: "backoff", input: "BACKOFF", expected: StateBackoff},
+		{name: "stopping", input: "STOPPING", expected: StateStopping},
+		{name: "exited", input: "EXITED", expected: StateExited},
+		{name: "fatal", input: "FATAL", expected: StateFatal},
+		{name: "unknown", input: "UNKNOWN", expected: StateUnknown},
+		{name: "unrecognized name", input: "BOGUS", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseProcessState(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expected, got)
+		})
+	}
+}
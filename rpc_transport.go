@@ -0,0 +1,543 @@
+package supervisorctl
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RPCTransport implements Transport by calling supervisord's XML-RPC API
+// directly over HTTP (or over a unix socket, supervisord's more common
+// listener). It talks to the same API surface as supervisorctl itself, so
+// it needs neither the supervisorctl binary nor a local config file, and it
+// can reach a remote supervisord.
+type RPCTransport struct {
+	httpClient *http.Client
+	endpoint   string
+	username   string
+	password   string
+}
+
+// Option configures an RPCTransport created by NewRPCClient.
+type Option func(*RPCTransport)
+
+// WithUnixSocket dials supervisord over the given unix socket (typically
+// /var/run/supervisor.sock) instead of TCP. The host portion of the URL
+// passed to NewRPCClient is ignored when this option is used.
+func WithUnixSocket(path string) Option {
+	return func(t *RPCTransport) {
+		client := *t.httpClient
+		client.Transport = &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, "unix", path)
+			},
+		}
+		t.httpClient = &client
+	}
+}
+
+// WithBasicAuth sets the credentials supervisord's inet_http_server (or the
+// HTTP proxy in front of a unix socket) expects.
+func WithBasicAuth(username, password string) Option {
+	return func(t *RPCTransport) {
+		t.username = username
+		t.password = password
+	}
+}
+
+// WithHTTPClient overrides the *http.Client used to reach supervisord,
+// e.g. to set timeouts or a custom transport. Applying WithUnixSocket after
+// WithHTTPClient preserves the injected client's other settings.
+func WithHTTPClient(client *http.Client) Option {
+	return func(t *RPCTransport) {
+		t.httpClient = client
+	}
+}
+
+// NewRPCClient creates a Client backed by supervisord's XML-RPC API,
+// reachable at url (e.g. "http://localhost:9001/RPC2", or
+// "http://unix/RPC2" combined with WithUnixSocket).
+func NewRPCClient(url string, opts ...Option) *Client {
+	t := &RPCTransport{
+		httpClient: &http.Client{Timeout: 30 * time.Second},
+		endpoint:   url,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return &Client{transport: t}
+}
+
+func (t *RPCTransport) call(ctx context.Context, method string, params ...string) (xmlrpcValue, error) {
+	args := make([]callArg, len(params))
+	for i, p := range params {
+		args[i] = stringArg(p)
+	}
+	return t.callArgs(ctx, method, args)
+}
+
+// callArgs is like call, but lets the caller tag each param with its
+// XML-RPC type (e.g. int), for methods whose signature doesn't accept a
+// string.
+func (t *RPCTransport) callArgs(ctx context.Context, method string, args []callArg) (xmlrpcValue, error) {
+	body, err := encodeMethodCallArgs(method, args)
+	if err != nil {
+		return xmlrpcValue{}, fmt.Errorf("encode %s call: %w", method, err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.endpoint, strings.NewReader(string(body)))
+	if err != nil {
+		return xmlrpcValue{}, fmt.Errorf("build %s request: %w", method, err)
+	}
+	req.Header.Set("Content-Type", "text/xml")
+	if t.username != "" {
+		req.SetBasicAuth(t.username, t.password)
+	}
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		return xmlrpcValue{}, fmt.Errorf("call %s: %w", method, err)
+	}
+	defer resp.Body.Close()
+
+	respBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return xmlrpcValue{}, fmt.Errorf("read %s response: %w", method, err)
+	}
+
+	value, err := decodeMethodResponse(respBody)
+	if err != nil {
+		return xmlrpcValue{}, fmt.Errorf("%s: %w", method, err)
+	}
+	return value, nil
+}
+
+// Status returns info for the given program names, or for every program if
+// names is empty.
+func (t *RPCTransport) Status(names []string) ([]ProgramInfo, error) {
+	ctx := context.Background()
+	if len(names) == 0 {
+		v, err := t.call(ctx, "supervisor.getAllProcessInfo")
+		if err != nil {
+			return nil, err
+		}
+		items := v.items()
+		programs := make([]ProgramInfo, 0, len(items))
+		for _, item := range items {
+			programs = append(programs, processInfoFromValue(item))
+		}
+		return programs, nil
+	}
+
+	programs := make([]ProgramInfo, 0, len(names))
+	for _, name := range names {
+		v, err := t.call(ctx, "supervisor.getProcessInfo", name)
+		if err != nil {
+			return nil, err
+		}
+		programs = append(programs, processInfoFromValue(v))
+	}
+	return programs, nil
+}
+
+func (t *RPCTransport) StartProcess(name string) error {
+	_, err := t.call(context.Background(), "supervisor.startProcess", name)
+	return err
+}
+
+func (t *RPCTransport) StopProcess(name string) error {
+	_, err := t.call(context.Background(), "supervisor.stopProcess", name)
+	return err
+}
+
+// RestartProcess restarts a process. Supervisor's RPC API has no single
+// "restart" method, so this stops then starts the process, matching what
+// supervisorctl itself does for its restart command. A process that's
+// already stopped is not an error here, same as supervisorctl's restart.
+func (t *RPCTransport) RestartProcess(name string) error {
+	if err := t.StopProcess(name); err != nil && !errors.Is(err, ErrNotRunning) {
+		return err
+	}
+	return t.StartProcess(name)
+}
+
+func (t *RPCTransport) StartProcessGroup(name string) error {
+	_, err := t.call(context.Background(), "supervisor.startProcessGroup", name)
+	return err
+}
+
+func (t *RPCTransport) StopProcessGroup(name string) error {
+	_, err := t.call(context.Background(), "supervisor.stopProcessGroup", name)
+	return err
+}
+
+// RestartProcessGroup restarts every process in a group. Supervisor's RPC
+// API has no single "restart group" method, so this stops then starts it.
+// A group that's already stopped is not an error here, same as
+// supervisorctl's restart.
+func (t *RPCTransport) RestartProcessGroup(name string) error {
+	if err := t.StopProcessGroup(name); err != nil && !errors.Is(err, ErrNotRunning) {
+		return err
+	}
+	return t.StartProcessGroup(name)
+}
+
+func (t *RPCTransport) AddProcessGroup(ctx context.Context, name string) error {
+	_, err := t.call(ctx, "supervisor.addProcessGroup", name)
+	return err
+}
+
+func (t *RPCTransport) RemoveProcessGroup(ctx context.Context, name string) error {
+	_, err := t.call(ctx, "supervisor.removeProcessGroup", name)
+	return err
+}
+
+// Reread calls supervisor.reloadConfig, which returns an array holding one
+// array of three arrays: [[added, changed, removed]] process group names.
+func (t *RPCTransport) Reread(ctx context.Context) (added, changed, removed []string, err error) {
+	v, err := t.call(ctx, "supervisor.reloadConfig")
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	outer := v.items()
+	if len(outer) == 0 {
+		return nil, nil, nil, nil
+	}
+	groups := outer[0].items()
+	if len(groups) > 0 {
+		added = stringsFromArray(groups[0])
+	}
+	if len(groups) > 1 {
+		changed = stringsFromArray(groups[1])
+	}
+	if len(groups) > 2 {
+		removed = stringsFromArray(groups[2])
+	}
+	return added, changed, removed, nil
+}
+
+// Update reproduces supervisorctl's "update" command: reread the config,
+// then add newly-added groups, and remove-then-re-add changed groups,
+// restricted to names if given.
+func (t *RPCTransport) Update(ctx context.Context, names ...string) error {
+	added, changed, removed, err := t.Reread(ctx)
+	if err != nil {
+		return err
+	}
+
+	wanted := func(name string) bool {
+		if len(names) == 0 {
+			return true
+		}
+		for _, n := range names {
+			if n == name {
+				return true
+			}
+		}
+		return false
+	}
+
+	for _, name := range removed {
+		if !wanted(name) {
+			continue
+		}
+		if err := t.StopProcessGroup(name); err != nil {
+			return err
+		}
+		if err := t.RemoveProcessGroup(ctx, name); err != nil {
+			return err
+		}
+	}
+	for _, name := range changed {
+		if !wanted(name) {
+			continue
+		}
+		if err := t.StopProcessGroup(name); err != nil {
+			return err
+		}
+		if err := t.RemoveProcessGroup(ctx, name); err != nil {
+			return err
+		}
+		if err := t.AddProcessGroup(ctx, name); err != nil {
+			return err
+		}
+	}
+	for _, name := range added {
+		if !wanted(name) {
+			continue
+		}
+		if err := t.AddProcessGroup(ctx, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Reload restarts supervisord itself (re-execing its process management
+// machinery), matching supervisorctl's "reload" command.
+func (t *RPCTransport) Reload(ctx context.Context) error {
+	_, err := t.call(ctx, "supervisor.restart")
+	return err
+}
+
+func (t *RPCTransport) Shutdown(ctx context.Context) error {
+	_, err := t.call(ctx, "supervisor.shutdown")
+	return err
+}
+
+func (t *RPCTransport) SignalProcesses(ctx context.Context, signal string, names ...string) error {
+	if len(names) == 0 {
+		_, err := t.call(ctx, "supervisor.signalAllProcesses", signal)
+		return err
+	}
+	for _, name := range names {
+		if _, err := t.call(ctx, "supervisor.signalProcess", name, signal); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *RPCTransport) ClearProcessLogs(ctx context.Context, names ...string) error {
+	if len(names) == 0 {
+		_, err := t.call(ctx, "supervisor.clearAllProcessLogs")
+		return err
+	}
+	for _, name := range names {
+		if _, err := t.call(ctx, "supervisor.clearProcessLog", name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Pid returns the PID of the named process, or supervisord's own PID if
+// name is empty.
+func (t *RPCTransport) Pid(ctx context.Context, name string) (int, error) {
+	if name == "" {
+		v, err := t.call(ctx, "supervisor.getPID")
+		if err != nil {
+			return 0, err
+		}
+		return v.asInt(), nil
+	}
+	v, err := t.call(ctx, "supervisor.getProcessInfo", name)
+	if err != nil {
+		return 0, err
+	}
+	return v.memberInt("pid"), nil
+}
+
+// Avail calls supervisor.getAllConfigInfo, which reports every configured
+// process group regardless of whether it's currently running.
+func (t *RPCTransport) Avail(ctx context.Context) ([]AvailEntry, error) {
+	v, err := t.call(ctx, "supervisor.getAllConfigInfo")
+	if err != nil {
+		return nil, err
+	}
+	items := v.items()
+	entries := make([]AvailEntry, 0, len(items))
+	for _, item := range items {
+		entries = append(entries, AvailEntry{
+			Name:      item.memberString("name"),
+			Group:     item.memberString("group"),
+			InUse:     item.memberBool("inuse"),
+			AutoStart: item.memberBool("autostart"),
+			Priority:  item.memberInt("priority"),
+		})
+	}
+	return entries, nil
+}
+
+// stringsFromArray converts an xmlrpc array value into a []string.
+func stringsFromArray(v xmlrpcValue) []string {
+	items := v.items()
+	out := make([]string, 0, len(items))
+	for _, item := range items {
+		out = append(out, item.asString())
+	}
+	return out
+}
+
+// processInfoFromValue converts one element of getAllProcessInfo (or the
+// return value of getProcessInfo) into a ProgramInfo.
+func processInfoFromValue(v xmlrpcValue) ProgramInfo {
+	state := ProcessState(v.memberInt("state"))
+	start := v.memberInt64("start")
+	stop := v.memberInt64("stop")
+	now := v.memberInt64("now")
+
+	var uptime time.Duration
+	if state == StateRunning || state == StateStopping {
+		uptime = time.Duration(now-start) * time.Second
+	}
+
+	info := ProgramInfo{
+		Name:        v.memberString("name"),
+		Description: v.memberString("description"),
+		State:       state,
+		PID:         v.memberInt("pid"),
+		Uptime:      uptime,
+		ExitStatus:  v.memberInt("exitstatus"),
+		SpawnErr:    v.memberString("spawnerr"),
+	}
+	if start > 0 {
+		info.StartTime = time.Unix(start, 0)
+	}
+	if stop > 0 {
+		info.StopTime = time.Unix(stop, 0)
+	}
+	return info
+}
+
+const (
+	defaultTailBytes        = 16384
+	defaultTailPollInterval = time.Second
+)
+
+// ReadLog calls supervisor.tailProcessStdoutLog or tailProcessStderrLog,
+// which returns the requested window of log data along with the offset to
+// resume from and whether the log grew past what this call could return.
+// If length is <= 0, it defaults to 1600, supervisorctl's own default.
+func (t *RPCTransport) ReadLog(ctx context.Context, name string, stream Stream, offset, length int) ([]byte, int, bool, error) {
+	if length <= 0 {
+		length = 1600
+	}
+	method := "supervisor.tailProcessStdoutLog"
+	if stream == StreamStderr {
+		method = "supervisor.tailProcessStderrLog"
+	}
+
+	v, err := t.callArgs(ctx, method, []callArg{stringArg(name), intArg(offset), intArg(length)})
+	if err != nil {
+		return nil, 0, false, err
+	}
+	items := v.items()
+	if len(items) != 3 {
+		return nil, 0, false, fmt.Errorf("%s: unexpected response shape", method)
+	}
+	return []byte(items[0].asString()), items[1].asInt(), items[2].asBool(), nil
+}
+
+// readLogFromEnd calls supervisor.readProcessStdoutLog or
+// readProcessStderrLog with a negative offset, which supervisor documents
+// as "N bytes before the end of the file" — the one-shot snapshot read
+// supervisorctl's own tail command uses to seed its initial view. Unlike
+// tailProcessStdoutLog/tailProcessStderrLog, these calls return only the
+// log data; there's no cursor or overflow flag to resume from, so Tail
+// uses this once to find a real starting offset, then polls via
+// ReadLog/tailProcess*Log from there.
+func (t *RPCTransport) readLogFromEnd(ctx context.Context, name string, stream Stream, length int) ([]byte, error) {
+	method := "supervisor.readProcessStdoutLog"
+	if stream == StreamStderr {
+		method = "supervisor.readProcessStderrLog"
+	}
+
+	v, err := t.callArgs(ctx, method, []callArg{stringArg(name), intArg(-length), intArg(0)})
+	if err != nil {
+		return nil, err
+	}
+	return []byte(v.asString()), nil
+}
+
+// Tail streams a program's log by polling ReadLog. The first read seeds
+// the starting offset from the end of whatever's already on disk; if
+// opts.Follow is set, it then polls at opts.PollInterval for new data.
+// Partial trailing lines are buffered across polls so LogLine boundaries
+// always fall on newlines.
+func (t *RPCTransport) Tail(ctx context.Context, name string, opts TailOptions) (<-chan LogLine, error) {
+	stream := opts.Stream
+	if stream == "" {
+		stream = StreamStdout
+	}
+	length := opts.Bytes
+	if length <= 0 {
+		length = defaultTailBytes
+	}
+	interval := opts.PollInterval
+	if interval <= 0 {
+		interval = defaultTailPollInterval
+	}
+
+	data, err := t.readLogFromEnd(ctx, name, stream, length)
+	if err != nil {
+		return nil, err
+	}
+	// tailProcessStdoutLog's offset is a forward cursor, not the negative
+	// "N bytes before the end" offset readLogFromEnd used above. A ReadLog
+	// at offset 0 (with ReadLog's default length) reports the log's current
+	// size as the offset to resume from; the data it returns is discarded,
+	// and the offset is the real cursor polling resumes from.
+	_, offset, _, err := t.ReadLog(ctx, name, stream, 0, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	ch := make(chan LogLine)
+	go func() {
+		defer close(ch)
+
+		var pending []byte
+		emit := func(chunk []byte) bool {
+			pending = append(pending, chunk...)
+			for {
+				i := bytes.IndexByte(pending, '\n')
+				if i < 0 {
+					return true
+				}
+				line := string(pending[:i])
+				pending = pending[i+1:]
+				select {
+				case ch <- LogLine{Name: name, Stream: stream, Line: line}:
+				case <-ctx.Done():
+					return false
+				}
+			}
+		}
+
+		if !emit(data) {
+			return
+		}
+		if !opts.Follow {
+			if len(pending) > 0 {
+				select {
+				case ch <- LogLine{Name: name, Stream: stream, Line: string(pending)}:
+				case <-ctx.Done():
+				}
+			}
+			return
+		}
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				chunk, newOffset, overflow, err := t.ReadLog(ctx, name, stream, offset, length)
+				if err != nil {
+					return
+				}
+				if overflow {
+					// The log grew past what one call could return; resync
+					// to the new offset instead of trying to catch up, to
+					// avoid an unbounded read loop.
+					pending = nil
+				}
+				offset = newOffset
+				if !emit(chunk) {
+					return
+				}
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -0,0 +1,167 @@
+package supervisorctl
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRPCTransportStatus(t *testing.T) {
+	tests := []struct {
+		name          string
+		names         []string
+		response      string
+		expectedLen   int
+		expectedError string
+	}{
+		{
+			name:  "all processes",
+			names: nil,
+			response: `<?xml version="1.0"?>
+<methodResponse><params><param><value><array><data>
+<value><struct>
+<member><name>name</name><value><string>program1</string></value></member>
+<member><name>description</name><value><string>pid 123, uptime 1:23:45</string></value></member>
+<member><name>state</name><value><int>20</int></value></member>
+<member><name>statename</name><value><string>RUNNING</string></value></member>
+<member><name>pid</name><value><int>123</int></value></member>
+<member><name>start</name><value><int>1000</int></value></member>
+<member><name>now</name><value><int>6005</int></value></member>
+</struct></value>
+</data></array></value></param></params></methodResponse>`,
+			expectedLen: 1,
+		},
+		{
+			name:          "fault",
+			names:         []string{"missing"},
+			response:      `<?xml version="1.0"?><methodResponse><fault><value><struct><member><name>faultCode</name><value><int>10</int></value></member><member><name>faultString</name><value><string>BAD_NAME</string></value></member></struct></value></fault></methodResponse>`,
+			expectedError: "BAD_NAME",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/xml")
+				_, _ = w.Write([]byte(tt.response))
+			}))
+			defer server.Close()
+
+			client := NewRPCClient(server.URL)
+			programs, err := client.transport.Status(tt.names)
+
+			if tt.expectedError != "" {
+				assert.Error(t, err)
+				assert.Contains(t, err.Error(), tt.expectedError)
+				assert.ErrorIs(t, err, ErrBadName)
+			} else {
+				assert.NoError(t, err)
+				assert.Len(t, programs, tt.expectedLen)
+				if tt.expectedLen > 0 {
+					assert.Equal(t, "program1", programs[0].Name)
+					assert.Equal(t, 123, programs[0].PID)
+					assert.Equal(t, 1*time.Hour+23*time.Minute+25*time.Second, programs[0].Uptime)
+				}
+			}
+		})
+	}
+}
+
+func TestRPCTransportReread(t *testing.T) {
+	response := `<?xml version="1.0"?><methodResponse><params><param><value><array><data>
+<value><array><data>
+<value><array><data><value><string>foo</string></value></data></array></value>
+<value><array><data></data></array></value>
+<value><array><data><value><string>bar</string></value></data></array></value>
+</data></array></value>
+</data></array></value></param></params></methodResponse>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	added, changed, removed, err := client.transport.Reread(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"foo"}, added)
+	assert.Empty(t, changed)
+	assert.Equal(t, []string{"bar"}, removed)
+}
+
+func TestRPCTransportAvail(t *testing.T) {
+	response := `<?xml version="1.0"?><methodResponse><params><param><value><array><data>
+<value><struct>
+<member><name>name</name><value><string>program1</string></value></member>
+<member><name>group</name><value><string>program1</string></value></member>
+<member><name>inuse</name><value><boolean>1</boolean></value></member>
+<member><name>autostart</name><value><boolean>0</boolean></value></member>
+<member><name>priority</name><value><int>999</int></value></member>
+</struct></value>
+</data></array></value></param></params></methodResponse>`
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(response))
+	}))
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	entries, err := client.transport.Avail(context.Background())
+
+	assert.NoError(t, err)
+	assert.Equal(t, []AvailEntry{{
+		Name:      "program1",
+		Group:     "program1",
+		InUse:     true,
+		AutoStart: false,
+		Priority:  999,
+	}}, entries)
+}
+
+func TestRPCTransportReadLogDefaultLength(t *testing.T) {
+	var gotBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		gotBody = string(body)
+		w.Header().Set("Content-Type", "text/xml")
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><array><data>
+<value><string></string></value>
+<value><int>0</int></value>
+<value><boolean>0</boolean></value>
+</data></array></value></param></params></methodResponse>`))
+	}))
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	_, _, _, err := client.transport.ReadLog(context.Background(), "program1", StreamStdout, 0, 0)
+
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(gotBody, "<int>1600</int>"))
+}
+
+func TestRPCTransportRestartProcessAlreadyStopped(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "stopProcess") {
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><fault><value><struct><member><name>faultCode</name><value><int>70</int></value></member><member><name>faultString</name><value><string>NOT_RUNNING</string></value></member></struct></value></fault></methodResponse>`))
+			return
+		}
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><boolean>1</boolean></value></param></params></methodResponse>`))
+	}))
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	err := client.Restart("program1")
+
+	assert.NoError(t, err)
+}
@@ -0,0 +1,18 @@
+package supervisorctl
+
+import (
+	"context"
+	"os"
+
+	"github.com/mperusso/supervisorctl-client/eventlistener"
+)
+
+// Subscribe runs an event listener on this process's stdin/stdout,
+// following supervisord's eventlistener protocol. Call it from a program
+// that supervisord has been configured to run as an [eventlistener:...],
+// so that its stdin/stdout are already wired to supervisord. handle is
+// invoked once per event; its Result tells supervisord whether the event
+// was processed successfully.
+func (c *Client) Subscribe(ctx context.Context, handle func(eventlistener.Event) eventlistener.Result) error {
+	return eventlistener.NewListener(os.Stdin, os.Stdout).Run(ctx, handle)
+}
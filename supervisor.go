@@ -1,56 +1,53 @@
+// Package supervisorctl is a client for supervisor (http://supervisord.org),
+// speaking to it either by shelling out to the supervisorctl binary or
+// directly over its XML-RPC API.
 package supervisorctl
 
 import (
-	"bufio"
-	"bytes"
-	"fmt"
-	"os/exec"
-	"strconv"
-	"strings"
+	"context"
+	"time"
 )
 
 // ProgramInfo represents the information about a supervisor program.
-// It contains details like name, state, PID, and uptime.
+// It contains details like name, state, PID, uptime, and (when the
+// process has exited at least once) its exit details.
 type ProgramInfo struct {
-	Name        string `json:"name"`        // Program name
-	Description string `json:"description"` // Program description
-	State       int    `json:"state"`       // Program state code
-	StateName   string `json:"state_name"`  // Program state name
-	PID         int    `json:"pid"`         // Process ID
-	Uptime      string `json:"uptime"`      // Program uptime
+	Name        string        `json:"name"`        // Program name
+	Description string        `json:"description"` // Program description
+	State       ProcessState  `json:"state"`       // Program state
+	PID         int           `json:"pid"`         // Process ID
+	Uptime      time.Duration `json:"uptime"`      // Time since the process started
+	StartTime   time.Time     `json:"start_time"`  // When the process was last started
+	StopTime    time.Time     `json:"stop_time"`   // When the process last stopped, if it has
+	ExitStatus  int           `json:"exit_status"` // Exit code from the process's last run
+	SpawnErr    string        `json:"spawn_err"`   // Reason the process failed to spawn, if it did
 }
 
-// Cmd interface for executing commands
-type Cmd interface {
-	Run() error
-	Output() ([]byte, error)
-}
-
-// CommandExecutor interface for executing commands
-type CommandExecutor interface {
-	Command(name string, arg ...string) Cmd
-}
-
-// DefaultCommandExecutor implements CommandExecutor using os/exec
-type DefaultCommandExecutor struct{}
-
-func (d *DefaultCommandExecutor) Command(name string, arg ...string) Cmd {
-	return exec.Command(name, arg...)
+// StateName returns the name State displays as, e.g. "RUNNING". It exists
+// for compatibility with the previous release, where ProgramInfo carried
+// this as a separate StateName string field; it will be removed in a
+// future release in favor of calling State.String() directly.
+func (p ProgramInfo) StateName() string {
+	return p.State.String()
 }
 
 // Client represents a supervisorctl client.
-// It maintains the configuration file path and provides methods to interact with supervisor.
+// It delegates every operation to a Transport, so the same API works
+// whether it's backed by the supervisorctl binary or by supervisord's
+// XML-RPC API.
 type Client struct {
-	configFile      string
-	commandExecutor CommandExecutor
+	transport Transport
 }
 
-// NewClient creates a new supervisorctl client.
+// NewClient creates a new supervisorctl client that shells out to the
+// supervisorctl binary, which must be on PATH.
 // If configFile is empty, it will use the default supervisor configuration.
 func NewClient(configFile string) *Client {
 	return &Client{
-		configFile:      configFile,
-		commandExecutor: &DefaultCommandExecutor{},
+		transport: &cliTransport{
+			configFile: configFile,
+			executor:   &DefaultCommandExecutor{},
+		},
 	}
 }
 
@@ -64,137 +61,111 @@ type StatusOptions struct {
 // Status returns the status of supervisor programs.
 // If no names are provided in opts, returns status for all programs.
 func (c *Client) Status(opts StatusOptions) ([]ProgramInfo, error) {
-	args := []string{"status"}
-	if len(opts.Names) > 0 {
-		args = append(args, opts.Names...)
-	}
-	if c.configFile != "" {
-		args = append([]string{"-c", c.configFile}, args...)
-	}
-	cmd := c.commandExecutor.Command("supervisorctl", args...)
-	output, err := cmd.Output()
-	if err != nil {
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			if exitErr.ExitCode() == 3 || exitErr.ExitCode() == 4 {
-			} else {
-				return nil, fmt.Errorf("failed to get status: %w - output %s ", err, string(output))
-			}
-		} else {
-			return nil, fmt.Errorf("failed to get status: %w", err)
-		}
-	}
-
-	var programs []ProgramInfo
-	scanner := bufio.NewScanner(bytes.NewReader(output))
-	for scanner.Scan() {
-		line := scanner.Text()
-		program, err := parseStatusLine(line)
-		if err != nil {
-			return nil, fmt.Errorf("failed to parse status line: %w", err)
-		}
-		programs = append(programs, program)
-	}
-
-	return programs, nil
+	return c.transport.Status(opts.Names)
 }
 
 // Start starts a supervisor program.
 func (c *Client) Start(programName string) error {
-	args := []string{"start", programName}
-	if c.configFile != "" {
-		args = append([]string{"-c", c.configFile}, args...)
-	}
-	cmd := c.commandExecutor.Command("supervisorctl", args...)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to start program: %w", err)
-	}
-	return nil
+	return c.transport.StartProcess(programName)
 }
 
 // Stop stops a supervisor program.
 func (c *Client) Stop(programName string) error {
-	args := []string{"stop", programName}
-	if c.configFile != "" {
-		args = append([]string{"-c", c.configFile}, args...)
-	}
-	cmd := c.commandExecutor.Command("supervisorctl", args...)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to stop program: %w", err)
-	}
-	return nil
+	return c.transport.StopProcess(programName)
 }
 
 // Restart restarts a supervisor program.
 func (c *Client) Restart(programName string) error {
-	args := []string{"restart", programName}
-	if c.configFile != "" {
-		args = append([]string{"-c", c.configFile}, args...)
-	}
-	cmd := c.commandExecutor.Command("supervisorctl", args...)
-	if err := cmd.Run(); err != nil {
-		return fmt.Errorf("failed to restart program: %w", err)
-	}
-	return nil
+	return c.transport.RestartProcess(programName)
 }
 
-// parseStatusLine parses a single line of supervisorctl status output.
-func parseStatusLine(line string) (ProgramInfo, error) {
-	parts := strings.Fields(line)
-	if len(parts) < 3 {
-		return ProgramInfo{}, fmt.Errorf("invalid status line: %s", line)
-	}
+// StartGroup starts every process in a program group.
+func (c *Client) StartGroup(groupName string) error {
+	return c.transport.StartProcessGroup(groupName)
+}
 
-	name := parts[0]
-	state := parts[1]
-	description := strings.Join(parts[2:], " ")
-
-	// Handle "no such process" error
-	if strings.Contains(description, "no such process") {
-		return ProgramInfo{
-			Name:        name,
-			State:       1000, // UNKNOWN
-			StateName:   "UNKNOWN",
-			Description: "no such process",
-		}, nil
-	}
+// StopGroup stops every process in a program group.
+func (c *Client) StopGroup(groupName string) error {
+	return c.transport.StopProcessGroup(groupName)
+}
 
-	stateMap := map[string]int{
-		"STOPPED":  0,
-		"STARTING": 10,
-		"RUNNING":  20,
-		"BACKOFF":  30,
-		"STOPPING": 40,
-		"EXITED":   100,
-		"FATAL":    200,
-		"UNKNOWN":  1000,
-	}
+// RestartGroup restarts every process in a program group.
+func (c *Client) RestartGroup(groupName string) error {
+	return c.transport.RestartProcessGroup(groupName)
+}
 
-	stateCode, ok := stateMap[state]
-	if !ok {
-		return ProgramInfo{}, fmt.Errorf("unknown state: %s", state)
-	}
+// Add activates a process group that's already present in supervisord's
+// config but not yet running under it.
+func (c *Client) Add(ctx context.Context, name string) error {
+	return c.transport.AddProcessGroup(ctx, name)
+}
 
-	var pid int
-	var uptime string
-	if strings.Contains(description, "pid") {
-		pidStr := strings.Split(description, "pid")[1]
-		pidStr = strings.TrimSpace(strings.Split(pidStr, ",")[0])
-		pid, _ = strconv.Atoi(pidStr)
-
-		if strings.Contains(description, "uptime") {
-			uptimeParts := strings.Split(description, "uptime")
-			if len(uptimeParts) > 1 {
-				uptime = strings.TrimSpace(strings.Split(uptimeParts[1], ",")[0])
-			}
-		}
-	}
+// Remove removes an active process group from supervisord so its config
+// can be dropped or changed; it must be stopped first.
+func (c *Client) Remove(ctx context.Context, name string) error {
+	return c.transport.RemoveProcessGroup(ctx, name)
+}
+
+// Reread tells supervisord to re-read its config files without applying
+// anything, reporting which process groups were added, changed, or
+// removed as a result.
+func (c *Client) Reread(ctx context.Context) (added, changed, removed []string, err error) {
+	return c.transport.Reread(ctx)
+}
+
+// Update re-reads supervisord's config and applies the result: newly
+// added groups are started, changed groups are reloaded, and removed
+// groups are torn down. If names is non-empty, only those groups are
+// touched.
+func (c *Client) Update(ctx context.Context, names ...string) error {
+	return c.transport.Update(ctx, names...)
+}
+
+// Reload restarts supervisord's process management, re-reading its config
+// from scratch.
+func (c *Client) Reload(ctx context.Context) error {
+	return c.transport.Reload(ctx)
+}
+
+// Shutdown tells supervisord to shut down.
+func (c *Client) Shutdown(ctx context.Context) error {
+	return c.transport.Shutdown(ctx)
+}
+
+// Signal sends signal (e.g. "HUP", "USR2") to the given processes, or to
+// every process if names is empty.
+func (c *Client) Signal(ctx context.Context, signal string, names ...string) error {
+	return c.transport.SignalProcesses(ctx, signal, names...)
+}
+
+// Clear truncates the stdout/stderr log files for the given processes, or
+// for every process if names is empty.
+func (c *Client) Clear(ctx context.Context, names ...string) error {
+	return c.transport.ClearProcessLogs(ctx, names...)
+}
+
+// Pid returns the PID of the named program, or of supervisord itself if
+// name is empty.
+func (c *Client) Pid(ctx context.Context, name string) (int, error) {
+	return c.transport.Pid(ctx, name)
+}
+
+// Avail lists every process group configured in supervisord, regardless
+// of whether it is currently running.
+func (c *Client) Avail(ctx context.Context) ([]AvailEntry, error) {
+	return c.transport.Avail(ctx)
+}
+
+// Tail streams a program's log as LogLine values on the returned channel,
+// which is closed when the log ends (if opts.Follow is false) or ctx is
+// cancelled.
+func (c *Client) Tail(ctx context.Context, name string, opts TailOptions) (<-chan LogLine, error) {
+	return c.transport.Tail(ctx, name, opts)
+}
 
-	return ProgramInfo{
-		Name:        name,
-		State:       stateCode,
-		StateName:   state,
-		Description: description,
-		PID:         pid,
-		Uptime:      uptime,
-	}, nil
+// ReadLog reads up to length bytes of a program's log starting at offset,
+// returning the data, the offset to resume from, and whether the log grew
+// past what could be returned in one call.
+func (c *Client) ReadLog(ctx context.Context, name string, stream Stream, offset, length int) ([]byte, int, bool, error) {
+	return c.transport.ReadLog(ctx, name, stream, offset, length)
 }
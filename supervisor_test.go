@@ -1,8 +1,14 @@
 package supervisorctl
 
 import (
+	"bytes"
+	"context"
 	"errors"
+	"fmt"
+	"io"
+	"os/exec"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -17,6 +23,11 @@ func (m *MockCommandExecutor) Command(name string, arg ...string) Cmd {
 	return args.Get(0).(Cmd)
 }
 
+func (m *MockCommandExecutor) CommandContext(ctx context.Context, name string, arg ...string) Cmd {
+	args := m.Called(name, arg)
+	return args.Get(0).(Cmd)
+}
+
 type MockCmd struct {
 	mock.Mock
 	MockOutput []byte
@@ -31,14 +42,29 @@ func (m *MockCmd) Output() ([]byte, error) {
 	return m.MockOutput, m.Err
 }
 
+func (m *MockCmd) StdoutPipe() (io.ReadCloser, error) {
+	return io.NopCloser(bytes.NewReader(m.MockOutput)), m.Err
+}
+
+func (m *MockCmd) Start() error {
+	return nil
+}
+
+func (m *MockCmd) Wait() error {
+	return m.Err
+}
+
 func TestNewClient(t *testing.T) {
 	client := NewClient("")
 	assert.NotNil(t, client)
-	assert.Empty(t, client.configFile)
+	cli, ok := client.transport.(*cliTransport)
+	assert.True(t, ok)
+	assert.Empty(t, cli.configFile)
 
 	client = NewClient("/path/to/config")
-	assert.NotNil(t, client)
-	assert.Equal(t, "/path/to/config", client.configFile)
+	cli, ok = client.transport.(*cliTransport)
+	assert.True(t, ok)
+	assert.Equal(t, "/path/to/config", cli.configFile)
 }
 
 func TestStatus(t *testing.T) {
@@ -46,7 +72,9 @@ func TestStatus(t *testing.T) {
 		name          string
 		opts          StatusOptions
 		output        string
+		err           error
 		expectedError error
+		expectPartial bool
 		expectedLen   int
 	}{
 		{
@@ -55,8 +83,7 @@ func TestStatus(t *testing.T) {
 			output: `program1 RUNNING pid 123, uptime 1:23:45
 program2 STOPPED not started
 program3 STARTING start in progress`,
-			expectedError: nil,
-			expectedLen:   3,
+			expectedLen: 3,
 		},
 		{
 			name: "successful status specific",
@@ -65,15 +92,25 @@ program3 STARTING start in progress`,
 			},
 			output: `program1 RUNNING pid 123, uptime 1:23:45
 program2 STOPPED not started`,
-			expectedError: nil,
-			expectedLen:   2,
+			expectedLen: 2,
 		},
 		{
 			name:          "command error",
 			opts:          StatusOptions{},
 			output:        "",
+			err:           errors.New("command failed"),
 			expectedError: errors.New("command failed"),
-			expectedLen:   0,
+		},
+		{
+			name: "partial status",
+			opts: StatusOptions{
+				Names: []string{"program1", "missing"},
+			},
+			output: `program1 RUNNING pid 123, uptime 1:23:45
+missing: ERROR (no such process)`,
+			err:           exitError(t, 3),
+			expectPartial: true,
+			expectedLen:   2,
 		},
 	}
 
@@ -82,7 +119,7 @@ program2 STOPPED not started`,
 			mockExecutor := new(MockCommandExecutor)
 			mockCmd := &MockCmd{
 				MockOutput: []byte(tt.output),
-				Err:        tt.expectedError,
+				Err:        tt.err,
 			}
 
 			args := []string{"status"}
@@ -93,16 +130,22 @@ program2 STOPPED not started`,
 			mockExecutor.On("Command", "supervisorctl", args).Return(mockCmd)
 
 			client := &Client{
-				configFile:      "",
-				commandExecutor: mockExecutor,
+				transport: &cliTransport{
+					configFile: "",
+					executor:   mockExecutor,
+				},
 			}
 
 			programs, err := client.Status(tt.opts)
 
-			if tt.expectedError != nil {
+			switch {
+			case tt.expectPartial:
+				assert.ErrorIs(t, err, ErrPartialStatus)
+				assert.Len(t, programs, tt.expectedLen)
+			case tt.expectedError != nil:
 				assert.Error(t, err)
 				assert.Nil(t, programs)
-			} else {
+			default:
 				assert.NoError(t, err)
 				assert.Len(t, programs, tt.expectedLen)
 			}
@@ -112,6 +155,19 @@ program2 STOPPED not started`,
 	}
 }
 
+// exitError runs a subprocess that exits with the given code, returning
+// the *exec.ExitError Go's os/exec produces for it, so tests can exercise
+// cliTransport's exit-code handling without depending on exec internals.
+func exitError(t *testing.T, code int) error {
+	t.Helper()
+	cmd := exec.Command("sh", "-c", fmt.Sprintf("exit %d", code))
+	err := cmd.Run()
+	if err == nil {
+		t.Fatalf("expected subprocess to exit %d, it exited 0", code)
+	}
+	return err
+}
+
 func TestStart(t *testing.T) {
 	tests := []struct {
 		name          string
@@ -140,8 +196,10 @@ func TestStart(t *testing.T) {
 			mockExecutor.On("Command", "supervisorctl", []string{"start", tt.programName}).Return(mockCmd)
 
 			client := &Client{
-				configFile:      "",
-				commandExecutor: mockExecutor,
+				transport: &cliTransport{
+					configFile: "",
+					executor:   mockExecutor,
+				},
 			}
 
 			err := client.Start(tt.programName)
@@ -185,8 +243,10 @@ func TestStop(t *testing.T) {
 			mockExecutor.On("Command", "supervisorctl", []string{"stop", tt.programName}).Return(mockCmd)
 
 			client := &Client{
-				configFile:      "",
-				commandExecutor: mockExecutor,
+				transport: &cliTransport{
+					configFile: "",
+					executor:   mockExecutor,
+				},
 			}
 
 			err := client.Stop(tt.programName)
@@ -230,8 +290,10 @@ func TestRestart(t *testing.T) {
 			mockExecutor.On("Command", "supervisorctl", []string{"restart", tt.programName}).Return(mockCmd)
 
 			client := &Client{
-				configFile:      "",
-				commandExecutor: mockExecutor,
+				transport: &cliTransport{
+					configFile: "",
+					executor:   mockExecutor,
+				},
 			}
 
 			err := client.Restart(tt.programName)
@@ -259,11 +321,10 @@ func TestParseStatusLine(t *testing.T) {
 			line: "program1 RUNNING pid 123, uptime 1:23:45",
 			expectedInfo: ProgramInfo{
 				Name:        "program1",
-				State:       20,
-				StateName:   "RUNNING",
+				State:       StateRunning,
 				Description: "pid 123, uptime 1:23:45",
 				PID:         123,
-				Uptime:      "1:23:45",
+				Uptime:      1*time.Hour + 23*time.Minute + 45*time.Second,
 			},
 			expectedError: nil,
 		},
@@ -272,8 +333,7 @@ func TestParseStatusLine(t *testing.T) {
 			line: "program2 STOPPED not started",
 			expectedInfo: ProgramInfo{
 				Name:        "program2",
-				State:       0,
-				StateName:   "STOPPED",
+				State:       StateStopped,
 				Description: "not started",
 			},
 			expectedError: nil,
@@ -283,8 +343,7 @@ func TestParseStatusLine(t *testing.T) {
 			line: "program3 ERROR (no such process)",
 			expectedInfo: ProgramInfo{
 				Name:        "program3",
-				State:       1000,
-				StateName:   "UNKNOWN",
+				State:       StateUnknown,
 				Description: "no such process",
 			},
 			expectedError: nil,
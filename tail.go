@@ -0,0 +1,36 @@
+package supervisorctl
+
+import "time"
+
+// Stream identifies one of a program's two log streams.
+type Stream string
+
+const (
+	StreamStdout Stream = "stdout"
+	StreamStderr Stream = "stderr"
+)
+
+// LogLine is one line read from a program's log, as delivered by Tail.
+type LogLine struct {
+	Name   string
+	Stream Stream
+	Line   string
+}
+
+// TailOptions configures Tail.
+type TailOptions struct {
+	// Stream selects stdout or stderr. Defaults to StreamStdout.
+	Stream Stream
+	// Follow keeps the channel open and delivers new lines as they're
+	// written, like "tail -f". If false, Tail delivers what's currently
+	// available and closes the channel.
+	Follow bool
+	// Bytes caps how much of the log is read per request. Zero uses a
+	// sensible default. cliTransport ignores it when Follow is set, since
+	// "supervisorctl tail -f" takes no byte count.
+	Bytes int
+	// PollInterval controls how often RPCTransport polls for new log data
+	// while following. Zero uses a sensible default. Ignored by cliTransport,
+	// which follows via "supervisorctl tail -f" instead of polling.
+	PollInterval time.Duration
+}
@@ -0,0 +1,67 @@
+package supervisorctl
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCLITransportTail(t *testing.T) {
+	mockExecutor := new(MockCommandExecutor)
+	mockCmd := &MockCmd{MockOutput: []byte("line one\nline two\n")}
+	mockExecutor.On("CommandContext", "supervisorctl", []string{"tail", "program1"}).Return(mockCmd)
+
+	client := &Client{transport: &cliTransport{executor: mockExecutor}}
+	ch, err := client.Tail(context.Background(), "program1", TailOptions{})
+	assert.NoError(t, err)
+
+	var lines []string
+	for line := range ch {
+		assert.Equal(t, "program1", line.Name)
+		assert.Equal(t, StreamStdout, line.Stream)
+		lines = append(lines, line.Line)
+	}
+	assert.Equal(t, []string{"line one", "line two"}, lines)
+}
+
+func TestRPCTransportTail(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/xml")
+		body, _ := io.ReadAll(r.Body)
+		if strings.Contains(string(body), "readProcessStdoutLog") {
+			// The one-shot snapshot read Tail uses to seed its initial
+			// display; unlike tailProcessStdoutLog, it returns a bare
+			// string, not a [data, offset, overflow] array.
+			_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><string>line one
+line two
+</string></value></param></params></methodResponse>`))
+			return
+		}
+		// Every tailProcessStdoutLog call in this test - the (0, 0) probe
+		// Tail uses to learn the real offset to resume from, and every
+		// subsequent poll - reports no new data.
+		_, _ = w.Write([]byte(`<?xml version="1.0"?><methodResponse><params><param><value><array><data>
+<value><string></string></value>
+<value><int>18</int></value>
+<value><boolean>0</boolean></value>
+</data></array></value></param></params></methodResponse>`))
+	}))
+	defer server.Close()
+
+	client := NewRPCClient(server.URL)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := client.Tail(ctx, "program1", TailOptions{Follow: true, PollInterval: 5 * time.Millisecond})
+	assert.NoError(t, err)
+
+	assert.Equal(t, "line one", (<-ch).Line)
+	assert.Equal(t, "line two", (<-ch).Line)
+	cancel()
+}
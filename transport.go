@@ -0,0 +1,50 @@
+package supervisorctl
+
+import "context"
+
+// Transport is the mechanism a Client uses to actually talk to supervisord.
+// Client itself only builds on top of Transport, so every operation works
+// the same way whether it's backed by shelling out to supervisorctl
+// (cliTransport, via NewClient) or by supervisord's XML-RPC API
+// (RPCTransport, via NewRPCClient).
+type Transport interface {
+	// Status returns info for the given program names, or for every program
+	// if names is empty.
+	Status(names []string) ([]ProgramInfo, error)
+	StartProcess(name string) error
+	StopProcess(name string) error
+	RestartProcess(name string) error
+	StartProcessGroup(name string) error
+	StopProcessGroup(name string) error
+	RestartProcessGroup(name string) error
+
+	AddProcessGroup(ctx context.Context, name string) error
+	RemoveProcessGroup(ctx context.Context, name string) error
+	// Reread tells supervisord to re-read its config and reports which
+	// process groups were added, changed, or removed as a result.
+	Reread(ctx context.Context) (added, changed, removed []string, err error)
+	// Update re-reads the config and applies any changes, limited to names
+	// if given.
+	Update(ctx context.Context, names ...string) error
+	Reload(ctx context.Context) error
+	Shutdown(ctx context.Context) error
+	// SignalProcesses sends signal to the given processes, or to every
+	// process if names is empty.
+	SignalProcesses(ctx context.Context, signal string, names ...string) error
+	// ClearProcessLogs clears the stdout/stderr log files for the given
+	// processes, or for every process if names is empty.
+	ClearProcessLogs(ctx context.Context, names ...string) error
+	// Pid returns the PID of the named process, or of supervisord itself if
+	// name is empty.
+	Pid(ctx context.Context, name string) (int, error)
+	Avail(ctx context.Context) ([]AvailEntry, error)
+
+	// Tail streams a program's log as LogLine values on the returned
+	// channel, which is closed when the log ends (if not following) or ctx
+	// is cancelled.
+	Tail(ctx context.Context, name string, opts TailOptions) (<-chan LogLine, error)
+	// ReadLog reads up to length bytes of a program's log starting at
+	// offset, returning the data, the offset to resume from, and whether
+	// the log grew past what could be returned in one call.
+	ReadLog(ctx context.Context, name string, stream Stream, offset, length int) ([]byte, int, bool, error)
+}
@@ -0,0 +1,190 @@
+package supervisorctl
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+)
+
+// This file implements just enough of XML-RPC to drive supervisord's API:
+// encoding method calls with string and int parameters, and decoding the struct,
+// array, int, boolean and string values supervisord sends back. It is not a
+// general-purpose XML-RPC client.
+
+type xmlrpcValue struct {
+	String  *string       `xml:"string"`
+	Int     *string       `xml:"int"`
+	I4      *string       `xml:"i4"`
+	Boolean *string       `xml:"boolean"`
+	Double  *string       `xml:"double"`
+	Array   *xmlrpcArray  `xml:"array"`
+	Struct  *xmlrpcStruct `xml:"struct"`
+	Text    string        `xml:",chardata"`
+}
+
+type xmlrpcArray struct {
+	Values []xmlrpcValue `xml:"data>value"`
+}
+
+type xmlrpcStruct struct {
+	Members []xmlrpcMember `xml:"member"`
+}
+
+type xmlrpcMember struct {
+	Name  string      `xml:"name"`
+	Value xmlrpcValue `xml:"value"`
+}
+
+type xmlrpcMethodResponse struct {
+	XMLName xml.Name      `xml:"methodResponse"`
+	Params  []xmlrpcParam `xml:"params>param"`
+	Fault   *xmlrpcValue  `xml:"fault>value"`
+}
+
+type xmlrpcParam struct {
+	Value xmlrpcValue `xml:"value"`
+}
+
+// asString returns the value as a string regardless of its declared type,
+// which is all supervisord's responses ever need.
+func (v xmlrpcValue) asString() string {
+	switch {
+	case v.String != nil:
+		return *v.String
+	case v.Int != nil:
+		return *v.Int
+	case v.I4 != nil:
+		return *v.I4
+	case v.Boolean != nil:
+		return *v.Boolean
+	case v.Double != nil:
+		return *v.Double
+	default:
+		return v.Text
+	}
+}
+
+func (v xmlrpcValue) asInt() int {
+	n, _ := strconv.Atoi(v.asString())
+	return n
+}
+
+func (v xmlrpcValue) asInt64() int64 {
+	n, _ := strconv.ParseInt(v.asString(), 10, 64)
+	return n
+}
+
+func (v xmlrpcValue) asBool() bool {
+	return v.asString() == "1"
+}
+
+// member looks up a struct member by name, returning the zero value (and
+// false) if it isn't present.
+func (v xmlrpcValue) member(name string) (xmlrpcValue, bool) {
+	if v.Struct == nil {
+		return xmlrpcValue{}, false
+	}
+	for _, m := range v.Struct.Members {
+		if m.Name == name {
+			return m.Value, true
+		}
+	}
+	return xmlrpcValue{}, false
+}
+
+func (v xmlrpcValue) memberString(name string) string {
+	m, _ := v.member(name)
+	return m.asString()
+}
+
+func (v xmlrpcValue) memberInt(name string) int {
+	m, _ := v.member(name)
+	return m.asInt()
+}
+
+func (v xmlrpcValue) memberInt64(name string) int64 {
+	m, _ := v.member(name)
+	return m.asInt64()
+}
+
+func (v xmlrpcValue) memberBool(name string) bool {
+	m, _ := v.member(name)
+	return m.asBool()
+}
+
+// items returns the elements of an array value, or nil if v isn't an array.
+func (v xmlrpcValue) items() []xmlrpcValue {
+	if v.Array == nil {
+		return nil
+	}
+	return v.Array.Values
+}
+
+// callArg is one <param> of an outgoing methodCall, tagged with its
+// XML-RPC type. Most calls only ever send strings (process/group/signal
+// names); supervisor.tailProcessStdoutLog/tailProcessStderrLog also need
+// int-typed offset/length, since supervisord's handlers reject a string
+// where an int is declared.
+type callArg struct {
+	tag   string
+	value string
+}
+
+func stringArg(s string) callArg { return callArg{tag: "string", value: s} }
+func intArg(n int) callArg       { return callArg{tag: "int", value: strconv.Itoa(n)} }
+
+// encodeMethodCall builds a methodCall request body for method with params
+// encoded as XML-RPC strings, which covers every call this client makes
+// except offset/length arguments (see encodeMethodCallArgs).
+func encodeMethodCall(method string, params ...string) ([]byte, error) {
+	args := make([]callArg, len(params))
+	for i, p := range params {
+		args[i] = stringArg(p)
+	}
+	return encodeMethodCallArgs(method, args)
+}
+
+// encodeMethodCallArgs builds a methodCall request body for method with
+// explicitly typed params.
+func encodeMethodCallArgs(method string, args []callArg) ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteString(xml.Header)
+	buf.WriteString("<methodCall><methodName>")
+	if err := xml.EscapeText(&buf, []byte(method)); err != nil {
+		return nil, err
+	}
+	buf.WriteString("</methodName><params>")
+	for _, a := range args {
+		buf.WriteString("<param><value><")
+		buf.WriteString(a.tag)
+		buf.WriteString(">")
+		if err := xml.EscapeText(&buf, []byte(a.value)); err != nil {
+			return nil, err
+		}
+		buf.WriteString("</")
+		buf.WriteString(a.tag)
+		buf.WriteString("></value></param>")
+	}
+	buf.WriteString("</params></methodCall>")
+	return buf.Bytes(), nil
+}
+
+// decodeMethodResponse parses a methodResponse body, returning the single
+// return value on success or an *RPCFault if supervisord reported one.
+func decodeMethodResponse(body []byte) (xmlrpcValue, error) {
+	var resp xmlrpcMethodResponse
+	if err := xml.Unmarshal(body, &resp); err != nil {
+		return xmlrpcValue{}, fmt.Errorf("decode xmlrpc response: %w", err)
+	}
+	if resp.Fault != nil {
+		return xmlrpcValue{}, &RPCFault{
+			Code:   resp.Fault.memberInt("faultCode"),
+			String: resp.Fault.memberString("faultString"),
+		}
+	}
+	if len(resp.Params) == 0 {
+		return xmlrpcValue{}, fmt.Errorf("xmlrpc response had no return value")
+	}
+	return resp.Params[0].Value, nil
+}
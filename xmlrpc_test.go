@@ -0,0 +1,44 @@
+package supervisorctl
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncodeMethodCall(t *testing.T) {
+	body, err := encodeMethodCall("supervisor.startProcess", "program1")
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(body), "<methodName>supervisor.startProcess</methodName>"))
+	assert.True(t, strings.Contains(string(body), "<string>program1</string>"))
+}
+
+func TestEncodeMethodCallArgs(t *testing.T) {
+	body, err := encodeMethodCallArgs("supervisor.tailProcessStdoutLog", []callArg{
+		stringArg("program1"),
+		intArg(-100),
+		intArg(100),
+	})
+	assert.NoError(t, err)
+	assert.True(t, strings.Contains(string(body), "<string>program1</string>"))
+	assert.True(t, strings.Contains(string(body), "<int>-100</int>"))
+	assert.True(t, strings.Contains(string(body), "<int>100</int>"))
+}
+
+func TestDecodeMethodResponseValue(t *testing.T) {
+	resp := `<?xml version="1.0"?><methodResponse><params><param><value><boolean>1</boolean></value></param></params></methodResponse>`
+	v, err := decodeMethodResponse([]byte(resp))
+	assert.NoError(t, err)
+	assert.True(t, v.asBool())
+}
+
+func TestDecodeMethodResponseFault(t *testing.T) {
+	resp := `<?xml version="1.0"?><methodResponse><fault><value><struct>
+<member><name>faultCode</name><value><int>70</int></value></member>
+<member><name>faultString</name><value><string>NOT_RUNNING</string></value></member>
+</struct></value></fault></methodResponse>`
+	_, err := decodeMethodResponse([]byte(resp))
+	assert.Error(t, err)
+	assert.ErrorIs(t, err, ErrNotRunning)
+}